@@ -0,0 +1,42 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"context"
+	"time"
+)
+
+// EvalTimeout sets a deadline of d on the context used to evaluate a
+// Program. WithEvalContext wraps the caller-supplied eval-time context with
+// context.WithTimeout so that the interpreter.Dispatcher's cancellation
+// check, and any comprehension loop driven by interpreter.IterateRange,
+// abort once d elapses instead of running the expression to completion.
+func EvalTimeout(d time.Duration) ProgramOption {
+	return func(o *programOptions) {
+		o.evalTimeout = d
+	}
+}
+
+// WithEvalContext returns ctx, or a descendant of ctx bound by the
+// configured EvalTimeout, along with the cancel func that releases its
+// resources. The cancel func is always non-nil and must be called by the
+// caller once evaluation finishes, even when no timeout was configured.
+func (o *programOptions) WithEvalContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.evalTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, o.evalTimeout)
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+func TestEvalTimeoutAbortsBeforeDispatch(t *testing.T) {
+	called := false
+	overloads := []*functions.OverloadContext{{
+		Operator: "noop",
+		Function: func(_ context.Context, _ ...ref.Val) ref.Val {
+			called = true
+			return types.True
+		},
+	}}
+	p, err := NewProgram(overloads, EvalTimeout(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("NewProgram() failed: %v", err)
+	}
+	// EvalTimeout binds its deadline inside EvalFunction, not at NewProgram,
+	// so this pause does not shorten it; a one-nanosecond timeout has expired
+	// by the time the Dispatcher checks the context either way.
+	time.Sleep(time.Millisecond)
+
+	out, err := p.EvalFunction(context.Background(), "noop")
+	if err != nil {
+		t.Fatalf("EvalFunction() failed: %v", err)
+	}
+	if called {
+		t.Error("Function handler was invoked despite an expired EvalTimeout")
+	}
+	if _, isErr := out.(*types.Err); !isErr {
+		t.Errorf("EvalFunction() = %v, wanted a canceled-evaluation error", out)
+	}
+}
+
+func TestEvalTimeoutUnsetRunsNormally(t *testing.T) {
+	overloads := []*functions.OverloadContext{{
+		Operator: "noop",
+		Function: func(_ context.Context, _ ...ref.Val) ref.Val {
+			return types.True
+		},
+	}}
+	p, err := NewProgram(overloads)
+	if err != nil {
+		t.Fatalf("NewProgram() failed: %v", err)
+	}
+	out, err := p.EvalFunction(context.Background(), "noop")
+	if err != nil {
+		t.Fatalf("EvalFunction() failed: %v", err)
+	}
+	if out != types.True {
+		t.Errorf("EvalFunction() = %v, wanted true", out)
+	}
+}
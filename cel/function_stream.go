@@ -0,0 +1,32 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import "github.com/google/cel-go/common/functions"
+
+// FunctionStream builds an *functions.OverloadContext backed by a
+// functions.StreamOp, suitable for passing to NewDispatcher alongside
+// ordinary overloads. It is registration sugar for functions whose results
+// come from a paginated API, DB cursor, or similar incremental source:
+//
+//	dispatcher, err := cel.NewDispatcher([]*functions.OverloadContext{
+//		cel.FunctionStream("listUsers", listUsersStream),
+//	})
+func FunctionStream(operator string, op functions.StreamOp) *functions.OverloadContext {
+	return &functions.OverloadContext{
+		Operator: operator,
+		Stream:   op,
+	}
+}
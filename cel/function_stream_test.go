@@ -0,0 +1,62 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+func TestFunctionStreamEvalStreamYieldsAllElements(t *testing.T) {
+	ch := make(chan ref.Val, 2)
+	ch <- types.Int(1)
+	ch <- types.Int(2)
+	close(ch)
+
+	overloads := []*functions.OverloadContext{
+		FunctionStream("pager", func(_ context.Context, _ ...ref.Val) (<-chan ref.Val, error) {
+			return ch, nil
+		}),
+	}
+	p, err := NewProgram(overloads)
+	if err != nil {
+		t.Fatalf("NewProgram() failed: %v", err)
+	}
+
+	out, err := p.EvalStream(context.Background(), "pager")
+	if err != nil {
+		t.Fatalf("EvalStream() failed: %v", err)
+	}
+	it := out.(traits.Iterable).Iterator()
+
+	var got []int64
+	for it.HasNext() == types.True {
+		got = append(got, int64(it.Next().(types.Int)))
+	}
+	want := []int64{1, 2}
+	if len(got) != len(want) {
+		t.Fatalf("iterated %v, wanted %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("iterated[%d] = %d, wanted %d", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"time"
+
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/interpreter"
+)
+
+// ProgramOption configures the Dispatcher used by a Program at eval time.
+type ProgramOption func(*programOptions)
+
+type programOptions struct {
+	unaryInterceptors    []functions.UnaryInterceptor
+	binaryInterceptors   []functions.BinaryInterceptor
+	functionInterceptors []functions.FunctionInterceptor
+	evalTimeout          time.Duration
+}
+
+// CustomInterceptors registers UnaryInterceptor, BinaryInterceptor, and
+// FunctionInterceptor chains that wrap every function and operator
+// invocation made by the resulting Program's Dispatcher. Interceptors run
+// outermost-first, in the order given, around the overload's own
+// Unary/Binary/Function handler.
+func CustomInterceptors(unary []functions.UnaryInterceptor, binary []functions.BinaryInterceptor, function []functions.FunctionInterceptor) ProgramOption {
+	return func(o *programOptions) {
+		o.unaryInterceptors = append(o.unaryInterceptors, unary...)
+		o.binaryInterceptors = append(o.binaryInterceptors, binary...)
+		o.functionInterceptors = append(o.functionInterceptors, function...)
+	}
+}
+
+// newProgramOptions folds a slice of ProgramOption into a single
+// programOptions value.
+func newProgramOptions(opts ...ProgramOption) *programOptions {
+	o := &programOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// NewDispatcher builds an interpreter.Dispatcher with overloads registered
+// and interceptors installed according to opts. Programs evaluate calls
+// exclusively through the returned Dispatcher, so CustomInterceptors run
+// for every dispatched unary, binary, and function overload. Stream
+// overloads dispatched via DispatchStream are not intercepted.
+func NewDispatcher(overloads []*functions.OverloadContext, opts ...ProgramOption) (interpreter.Dispatcher, error) {
+	o := newProgramOptions(opts...)
+	d := interpreter.NewDispatcher()
+	if err := d.Add(overloads...); err != nil {
+		return nil, err
+	}
+	d.SetInterceptors(o.unaryInterceptors, o.binaryInterceptors, o.functionInterceptors)
+	return d, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+func TestCustomInterceptorsWrapEvalUnary(t *testing.T) {
+	var trace []string
+	unary := []functions.UnaryInterceptor{
+		func(ctx context.Context, operator string, value ref.Val, handler functions.UnaryOpContext) ref.Val {
+			trace = append(trace, "before")
+			out := handler(ctx, value)
+			trace = append(trace, "after")
+			return out
+		},
+	}
+	overloads := []*functions.OverloadContext{{
+		Operator: "negate",
+		Unary: func(_ context.Context, v ref.Val) ref.Val {
+			trace = append(trace, "handler")
+			return types.Bool(!bool(v.(types.Bool)))
+		},
+	}}
+	p, err := NewProgram(overloads, CustomInterceptors(unary, nil, nil))
+	if err != nil {
+		t.Fatalf("NewProgram() failed: %v", err)
+	}
+	out, err := p.EvalUnary(context.Background(), "negate", types.True)
+	if err != nil {
+		t.Fatalf("EvalUnary() failed: %v", err)
+	}
+	if out != types.False {
+		t.Errorf("EvalUnary() = %v, wanted false", out)
+	}
+	wantTrace := []string{"before", "handler", "after"}
+	if len(trace) != len(wantTrace) {
+		t.Fatalf("trace = %v, wanted %v", trace, wantTrace)
+	}
+	for i := range wantTrace {
+		if trace[i] != wantTrace[i] {
+			t.Errorf("trace[%d] = %q, wanted %q", i, trace[i], wantTrace[i])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"context"
+
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+)
+
+// Program is the eval-time entry point for a set of registered overloads.
+// It wraps an interpreter.Dispatcher configured according to the
+// ProgramOption values passed to NewProgram, so EvalTimeout applies to every
+// call made through it and CustomInterceptors apply to every EvalUnary,
+// EvalBinary, and EvalFunction call; EvalStream results are not intercepted.
+type Program struct {
+	dispatcher interpreter.Dispatcher
+	opts       *programOptions
+}
+
+// NewProgram builds a Program from overloads and opts.
+func NewProgram(overloads []*functions.OverloadContext, opts ...ProgramOption) (*Program, error) {
+	o := newProgramOptions(opts...)
+	d, err := NewDispatcher(overloads, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{dispatcher: d, opts: o}, nil
+}
+
+// EvalUnary evaluates the unary overload registered for operator. If
+// EvalTimeout was configured, ctx is bound by it before dispatch, so the
+// Dispatcher's cancellation check aborts the call once the deadline passes.
+func (p *Program) EvalUnary(ctx context.Context, operator string, value ref.Val) (ref.Val, error) {
+	ctx, cancel := p.opts.WithEvalContext(ctx)
+	defer cancel()
+	return p.dispatcher.DispatchUnary(ctx, operator, value)
+}
+
+// EvalBinary evaluates the binary overload registered for operator, subject
+// to the same EvalTimeout binding as EvalUnary.
+func (p *Program) EvalBinary(ctx context.Context, operator string, lhs, rhs ref.Val) (ref.Val, error) {
+	ctx, cancel := p.opts.WithEvalContext(ctx)
+	defer cancel()
+	return p.dispatcher.DispatchBinary(ctx, operator, lhs, rhs)
+}
+
+// EvalFunction evaluates the function overload registered for operator,
+// subject to the same EvalTimeout binding as EvalUnary.
+func (p *Program) EvalFunction(ctx context.Context, operator string, values ...ref.Val) (ref.Val, error) {
+	ctx, cancel := p.opts.WithEvalContext(ctx)
+	defer cancel()
+	return p.dispatcher.DispatchFunction(ctx, operator, values...)
+}
+
+// EvalStream evaluates the stream overload registered for operator, subject
+// to the same EvalTimeout binding as EvalUnary. Unlike EvalUnary/EvalBinary/
+// EvalFunction, the result is a lazily-read *types.StreamValue rather than a
+// value computed synchronously, so EvalStream cannot defer the cancel func
+// returned by WithEvalContext — doing so would cancel ctx before the caller
+// ever reads the stream. Instead, ownership of cancel is handed to the
+// StreamValue itself, which releases it once iteration observes the end of
+// the stream; see types.StreamValue.WithCancel.
+func (p *Program) EvalStream(ctx context.Context, operator string, values ...ref.Val) (ref.Val, error) {
+	ctx, cancel := p.opts.WithEvalContext(ctx)
+	val, err := p.dispatcher.DispatchStream(ctx, operator, values...)
+	sv, ok := val.(*types.StreamValue)
+	if !ok {
+		cancel()
+		return val, err
+	}
+	return sv.WithCancel(cancel), err
+}
@@ -18,6 +18,7 @@ package functions
 import (
 	"context"
 
+	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
 )
 
@@ -82,11 +83,27 @@ type OverloadContext struct {
 	// nil.
 	Function FunctionOpContext
 
+	// Stream defines the overload with a StreamOp implementation, for
+	// overloads backed by paginated APIs, DB cursors, or similar incremental
+	// sources. May be nil. Mutually exclusive with Unary, Binary, and
+	// Function in practice, though the dispatcher does not enforce this.
+	Stream StreamOp
+
 	// NonStrict specifies whether the Overload will tolerate arguments that
 	// are types.Err or types.Unknown.
 	NonStrict bool
 }
 
+// StreamOp is a function that takes zero or more values and produces a
+// channel of results rather than a single ref.Val. The dispatcher adapts the
+// channel into a lazily-iterated *types.StreamValue so that callers can
+// consume results in a comprehension without buffering the whole result set.
+// A StreamValue is iterable only; it is not a list and supports neither
+// size nor index access.
+// Implementations must close the returned channel once it is exhausted and
+// must stop sending once ctx is done.
+type StreamOp func(ctx context.Context, values ...ref.Val) (<-chan ref.Val, error)
+
 // UnaryOp is a function that takes a single value and produces an output.
 type UnaryOp func(value ref.Val) ref.Val
 
@@ -107,6 +124,87 @@ type FunctionOp func(values ...ref.Val) ref.Val
 // a value or error as a result.
 type FunctionOpContext func(ctx context.Context, values ...ref.Val) ref.Val
 
+// CheckCanceled reports whether ctx has already been canceled or has
+// exceeded its deadline. When it has, the returned ref.Val is a types.Err
+// describing the cancellation and ok is true; callers should short-circuit
+// dispatch and return the error instead of invoking the overload's
+// UnaryOpContext, BinaryOpContext, or FunctionOpContext handler. When ctx is
+// still live, ok is false and the returned value is nil.
+func CheckCanceled(ctx context.Context) (val ref.Val, ok bool) {
+	select {
+	case <-ctx.Done():
+		return types.NewErr("evaluation canceled: %v", ctx.Err()), true
+	default:
+		return nil, false
+	}
+}
+
+// UnaryInterceptor wraps a UnaryOpContext invocation, allowing callers to
+// layer cross-cutting behavior (tracing, metrics, quota enforcement, argument
+// redaction, caching, etc.) around the overload's handler. The handler
+// passed to an interceptor is either the next interceptor in the chain or,
+// for the innermost interceptor, the OverloadContext's own Unary field.
+type UnaryInterceptor func(ctx context.Context, operator string, value ref.Val, handler UnaryOpContext) ref.Val
+
+// BinaryInterceptor wraps a BinaryOpContext invocation. See UnaryInterceptor
+// for the general interceptor contract.
+type BinaryInterceptor func(ctx context.Context, operator string, lhs, rhs ref.Val, handler BinaryOpContext) ref.Val
+
+// FunctionInterceptor wraps a FunctionOpContext invocation. See
+// UnaryInterceptor for the general interceptor contract.
+type FunctionInterceptor func(ctx context.Context, operator string, values []ref.Val, handler FunctionOpContext) ref.Val
+
+// ChainUnaryInterceptors folds a slice of UnaryInterceptor values into a
+// single UnaryInterceptor, invoked outermost-first. A nil slice returns a
+// no-op interceptor that simply calls the handler.
+func ChainUnaryInterceptors(interceptors ...UnaryInterceptor) UnaryInterceptor {
+	return func(ctx context.Context, operator string, value ref.Val, handler UnaryOpContext) ref.Val {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor := interceptors[i]
+			next := chained
+			chained = func(ctx context.Context, value ref.Val) ref.Val {
+				return interceptor(ctx, operator, value, next)
+			}
+		}
+		return chained(ctx, value)
+	}
+}
+
+// ChainBinaryInterceptors folds a slice of BinaryInterceptor values into a
+// single BinaryInterceptor, invoked outermost-first. A nil slice returns a
+// no-op interceptor that simply calls the handler.
+func ChainBinaryInterceptors(interceptors ...BinaryInterceptor) BinaryInterceptor {
+	return func(ctx context.Context, operator string, lhs, rhs ref.Val, handler BinaryOpContext) ref.Val {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor := interceptors[i]
+			next := chained
+			chained = func(ctx context.Context, lhs, rhs ref.Val) ref.Val {
+				return interceptor(ctx, operator, lhs, rhs, next)
+			}
+		}
+		return chained(ctx, lhs, rhs)
+	}
+}
+
+// ChainFunctionInterceptors folds a slice of FunctionInterceptor values into
+// a single FunctionInterceptor, invoked outermost-first. A nil slice returns
+// a no-op interceptor that simply calls the handler.
+func ChainFunctionInterceptors(interceptors ...FunctionInterceptor) FunctionInterceptor {
+	return func(ctx context.Context, operator string, values []ref.Val, handler FunctionOpContext) ref.Val {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor := interceptors[i]
+			next := chained
+			chained = func(ctx context.Context, values ...ref.Val) ref.Val {
+				return interceptor(ctx, operator, values, next)
+			}
+		}
+		return chained(ctx, values...)
+	}
+}
+
 // ToOverloadContext convert to OverloadContext.
 func (o *Overload) ToOverloadContext() *OverloadContext {
 	ret := &OverloadContext{
@@ -132,7 +230,9 @@ func (o *Overload) ToOverloadContext() *OverloadContext {
 	return ret
 }
 
-// ToOverload convert to Overload.
+// ToOverload convert to Overload. A Stream implementation has no legacy,
+// context-free equivalent and is dropped; callers that register a Stream
+// overload must dispatch it as an OverloadContext.
 func (o *OverloadContext) ToOverload() *Overload {
 	ret := &Overload{
 		Operator:     o.Operator,
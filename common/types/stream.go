@@ -0,0 +1,194 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// StreamType is the ref.Type of all StreamValue instances.
+var StreamType = NewOpaqueType("stream")
+
+// streamIteratorType is the ref.Type of the traits.Iterator returned by
+// StreamValue.Iterator.
+var streamIteratorType = NewOpaqueType("stream.iterator")
+
+// StreamValue adapts a channel of ref.Val — typically produced by a
+// functions.StreamOp overload backed by a paginated API, DB cursor, or
+// similar incremental source — into a traits.Iterable. Comprehensions such
+// as @iter, map, filter, and exists can pull elements from a StreamValue on
+// demand, so the full result set never needs to be buffered in memory.
+type StreamValue struct {
+	ctx    context.Context
+	ch     <-chan ref.Val
+	cancel context.CancelFunc
+}
+
+// NewStreamValue creates a StreamValue that lazily pulls from ch until the
+// channel is closed or ctx is done, whichever happens first. Once ctx is
+// done, Iterator() stops yielding further elements even if ch still has
+// values buffered.
+func NewStreamValue(ctx context.Context, ch <-chan ref.Val) *StreamValue {
+	return &StreamValue{ctx: ctx, ch: ch}
+}
+
+// WithCancel attaches cancel to s and returns s. Each iterator obtained from
+// s invokes cancel at most once: when it observes the end of the stream —
+// either the channel closing or ctx.Done() firing — or when it is closed.
+// cancel is not invoked if s is never iterated. This lets a caller that derived ctx from
+// an eval-time context (e.g. via an EvalTimeout) hand the StreamValue
+// ownership of releasing it instead of canceling ctx before the stream has
+// been read.
+func (s *StreamValue) WithCancel(cancel context.CancelFunc) *StreamValue {
+	s.cancel = cancel
+	return s
+}
+
+// ConvertToNative implements ref.Val.ConvertToNative.
+func (s *StreamValue) ConvertToNative(typeDesc reflect.Type) (interface{}, error) {
+	return nil, fmt.Errorf("type conversion not supported for stream values")
+}
+
+// ConvertToType implements ref.Val.ConvertToType.
+func (s *StreamValue) ConvertToType(typeValue ref.Type) ref.Val {
+	return NewErr("type conversion not supported for stream values")
+}
+
+// Equal implements ref.Val.Equal. A StreamValue is only equal to itself;
+// comparing unread elements would require fully draining the channel.
+func (s *StreamValue) Equal(other ref.Val) ref.Val {
+	o, ok := other.(*StreamValue)
+	return Bool(ok && o == s)
+}
+
+// Type implements ref.Val.Type.
+func (s *StreamValue) Type() ref.Type {
+	return StreamType
+}
+
+// Value implements ref.Val.Value.
+func (s *StreamValue) Value() interface{} {
+	return s.ch
+}
+
+// Iterator implements traits.Iterable.
+func (s *StreamValue) Iterator() traits.Iterator {
+	return &streamIterator{ctx: s.ctx, ch: s.ch, cancel: s.cancel}
+}
+
+// streamIterator adapts a channel into a traits.Iterator. It pulls one
+// element ahead of the caller so that HasNext can report accurately without
+// consuming an element Next hasn't returned yet.
+type streamIterator struct {
+	ctx    context.Context
+	ch     <-chan ref.Val
+	next   ref.Val
+	done   bool
+	cancel context.CancelFunc
+}
+
+// ConvertToNative implements ref.Val.ConvertToNative.
+func (it *streamIterator) ConvertToNative(typeDesc reflect.Type) (interface{}, error) {
+	return nil, fmt.Errorf("type conversion not supported for stream iterators")
+}
+
+// ConvertToType implements ref.Val.ConvertToType.
+func (it *streamIterator) ConvertToType(typeValue ref.Type) ref.Val {
+	return NewErr("type conversion not supported for stream iterators")
+}
+
+// Equal implements ref.Val.Equal.
+func (it *streamIterator) Equal(other ref.Val) ref.Val {
+	o, ok := other.(*streamIterator)
+	return Bool(ok && o == it)
+}
+
+// Type implements ref.Val.Type.
+func (it *streamIterator) Type() ref.Type {
+	return streamIteratorType
+}
+
+// Value implements ref.Val.Value.
+func (it *streamIterator) Value() interface{} {
+	return it.ch
+}
+
+// HasNext implements traits.Iterator. Once ctx is done, HasNext reports
+// false even if the underlying channel still has buffered elements, so
+// iteration stops at the next boundary instead of draining the channel.
+func (it *streamIterator) HasNext() ref.Val {
+	if it.next != nil {
+		return True
+	}
+	if it.done {
+		return False
+	}
+	select {
+	case <-it.ctx.Done():
+		it.markDone()
+		return False
+	default:
+	}
+	select {
+	case <-it.ctx.Done():
+		it.markDone()
+		return False
+	case v, ok := <-it.ch:
+		if !ok {
+			it.markDone()
+			return False
+		}
+		it.next = v
+		return True
+	}
+}
+
+// markDone records that the stream is exhausted and releases the cancel
+// func attached via StreamValue.WithCancel, if any, so resources tied to a
+// derived eval-time context are freed as soon as the stream is drained
+// rather than before it is ever read.
+func (it *streamIterator) markDone() {
+	it.done = true
+	if it.cancel != nil {
+		it.cancel()
+		it.cancel = nil
+	}
+}
+
+// Close releases the cancel func attached via StreamValue.WithCancel, if
+// any, canceling the ctx the StreamOp producer was invoked with. Callers
+// that stop consuming an iterator before it is exhausted — e.g. the exists
+// comprehension short-circuiting on the first match — must call Close so
+// the eval-context's cancel is still released and the producer is told via
+// ctx.Done() to stop sending, rather than running until the caller's
+// parent context ends on its own. Close is idempotent.
+func (it *streamIterator) Close() {
+	it.markDone()
+}
+
+// Next implements traits.Iterator.
+func (it *streamIterator) Next() ref.Val {
+	if it.next == nil && it.HasNext() != True {
+		return nil
+	}
+	v := it.next
+	it.next = nil
+	return v
+}
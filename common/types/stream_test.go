@@ -0,0 +1,121 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/cel-go/common/types/ref"
+)
+
+func TestStreamValueIteratesLazily(t *testing.T) {
+	ch := make(chan ref.Val, 1)
+	go func() {
+		defer close(ch)
+		ch <- Int(1)
+		ch <- Int(2)
+		ch <- Int(3)
+	}()
+
+	s := NewStreamValue(context.Background(), ch)
+	it := s.Iterator()
+
+	var got []int64
+	for it.HasNext() == True {
+		got = append(got, int64(it.Next().(Int)))
+	}
+	want := []int64{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("iterated %v, wanted %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("iterated[%d] = %d, wanted %d", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStreamValueStopsOnCancellation(t *testing.T) {
+	ch := make(chan ref.Val)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := NewStreamValue(ctx, ch)
+	it := s.Iterator()
+	if it.HasNext() != False {
+		t.Error("HasNext() = true on a canceled context, wanted false")
+	}
+}
+
+// streamCloser mirrors the unexported interface interpreter.IterateRange
+// type-asserts for: an iterator that needs to release resources when a
+// comprehension stops consuming it before it is exhausted.
+type streamCloser interface {
+	Close()
+}
+
+func TestStreamValueIteratorClosesOnEarlyExit(t *testing.T) {
+	ch := make(chan ref.Val, 2)
+	ch <- Int(1)
+	ch <- Int(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := false
+	s := NewStreamValue(ctx, ch).WithCancel(func() {
+		canceled = true
+		cancel()
+	})
+	it := s.Iterator()
+
+	if it.HasNext() != True || it.Next() != Int(1) {
+		t.Fatalf("expected to read one element before stopping early")
+	}
+	closer, ok := it.(streamCloser)
+	if !ok {
+		t.Fatalf("%T does not implement Close()", it)
+	}
+	closer.Close()
+
+	if !canceled {
+		t.Error("Close() did not release the cancel func attached via WithCancel")
+	}
+	if ctx.Err() == nil {
+		t.Error("Close() did not cancel ctx, so the StreamOp producer is never told to stop")
+	}
+	if it.HasNext() != False {
+		t.Error("HasNext() = true after Close(), wanted false")
+	}
+}
+
+func TestStreamValueIteratorCloseAfterDrainIsIdempotent(t *testing.T) {
+	ch := make(chan ref.Val, 1)
+	ch <- Int(1)
+	close(ch)
+
+	calls := 0
+	s := NewStreamValue(context.Background(), ch).WithCancel(func() { calls++ })
+	it := s.Iterator()
+	for it.HasNext() == True {
+		it.Next()
+	}
+	closer := it.(streamCloser)
+	closer.Close()
+	closer.Close()
+
+	if calls != 1 {
+		t.Errorf("cancel func invoked %d times, wanted exactly 1", calls)
+	}
+}
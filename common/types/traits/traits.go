@@ -0,0 +1,50 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package traits defines interfaces that a type may implement to participate
+// in operator overloads and function dispatch, along with bit flags
+// describing those traits for use with OverloadContext.OperandTrait.
+package traits
+
+const (
+	// AdderType types provide a '+' operator overload.
+	AdderType = 1 << iota
+
+	// ComparerType types support ordering comparisons '<', '<=', '>', '>='.
+	ComparerType
+
+	// ContainerType types support 'in' operations.
+	ContainerType
+
+	// IndexerType types support index access '[]'.
+	IndexerType
+
+	// IterableType types can be iterated over, typically in a comprehension.
+	IterableType
+
+	// IteratorType types support iteration over a set of values.
+	IteratorType
+
+	// MatcherType types support pattern matching via 'matches'.
+	MatcherType
+
+	// NegatorType types support negation via '-' or '!'.
+	NegatorType
+
+	// ReceiverType types support dynamic dispatch to receiver methods.
+	ReceiverType
+
+	// SizerType types support the size() function.
+	SizerType
+)
@@ -0,0 +1,61 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"context"
+
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// IterateRange drives the @iter, map, filter, and exists comprehension
+// loops over iter. Before each iteration it checks ctx via
+// functions.CheckCanceled; if ctx has been canceled or its deadline has
+// passed, IterateRange stops immediately and returns the resulting error
+// value instead of visiting the remaining elements. Otherwise it calls
+// visit with each element in turn, stopping early if visit returns false
+// (used by exists to short-circuit once a match is found).
+//
+// result is the accumulator returned once the loop finishes or exits early
+// via visit (a canceled loop returns the error value instead); callers seed it with the comprehension's
+// initial accumulator value and have visit return the updated value.
+//
+// If iter also implements a Close() method — as *types.StreamValue's
+// iterator does, to release a derived eval-context and signal its
+// producer to stop — IterateRange calls it on every exit path, not only
+// when iter is drained by HasNext, so exists short-circuiting on the
+// first match still releases the iterator's resources.
+func IterateRange(ctx context.Context, iter traits.Iterator, result ref.Val, visit func(ref.Val, ref.Val) (ref.Val, bool)) ref.Val {
+	if closer, ok := iter.(interface{ Close() }); ok {
+		defer closer.Close()
+	}
+	for {
+		if errVal, canceled := functions.CheckCanceled(ctx); canceled {
+			return errVal
+		}
+		if iter.HasNext() != types.True {
+			return result
+		}
+		next := iter.Next()
+		var cont bool
+		result, cont = visit(result, next)
+		if !cont {
+			return result
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// countingIterator is a self-contained traits.Iterator fixture that walks a
+// fixed list of values, independent of any production ref.Val adapter, so
+// this test doesn't depend on an unrelated backlog item's iterable type.
+type countingIterator struct {
+	values  []ref.Val
+	pos     int
+	visited int
+}
+
+func (it *countingIterator) HasNext() ref.Val {
+	return types.Bool(it.pos < len(it.values))
+}
+
+func (it *countingIterator) Next() ref.Val {
+	v := it.values[it.pos]
+	it.pos++
+	it.visited++
+	return v
+}
+
+func (it *countingIterator) ConvertToNative(typeDesc reflect.Type) (interface{}, error) {
+	return nil, nil
+}
+
+func (it *countingIterator) ConvertToType(typeValue ref.Type) ref.Val {
+	return it
+}
+
+func (it *countingIterator) Equal(other ref.Val) ref.Val {
+	o, ok := other.(*countingIterator)
+	return types.Bool(ok && o == it)
+}
+
+func (it *countingIterator) Type() ref.Type {
+	return nil
+}
+
+func (it *countingIterator) Value() interface{} {
+	return it.values
+}
+
+// closingIterator wraps countingIterator and records whether Close was
+// called, standing in for *types.StreamValue's iterator without pulling in
+// an unrelated backlog item's channel plumbing.
+type closingIterator struct {
+	countingIterator
+	closed bool
+}
+
+func (it *closingIterator) Close() {
+	it.closed = true
+}
+
+func TestIterateRangeClosesIteratorOnEarlyExit(t *testing.T) {
+	values := []ref.Val{types.Int(1), types.Int(2), types.Int(3)}
+	it := &closingIterator{countingIterator: countingIterator{values: values}}
+
+	result := IterateRange(context.Background(), it, types.False, func(_, next ref.Val) (ref.Val, bool) {
+		if next == types.Int(1) {
+			return types.True, false
+		}
+		return types.False, true
+	})
+
+	if result != types.True {
+		t.Errorf("IterateRange() = %v, wanted true", result)
+	}
+	if it.visited != 1 {
+		t.Errorf("iterator advanced %d times, wanted exactly 1 before short-circuiting", it.visited)
+	}
+	if !it.closed {
+		t.Error("Close() was not called after visit short-circuited the loop")
+	}
+}
+
+func TestIterateRangeClosesIteratorOnCompletion(t *testing.T) {
+	values := []ref.Val{types.Int(1), types.Int(2), types.Int(3)}
+	it := &closingIterator{countingIterator: countingIterator{values: values}}
+
+	IterateRange(context.Background(), it, types.Int(0), func(acc, next ref.Val) (ref.Val, bool) {
+		return acc.(types.Int) + next.(types.Int), true
+	})
+
+	if !it.closed {
+		t.Error("Close() was not called once the iterator was fully drained")
+	}
+}
+
+func TestIterateRangeStopsOnCancellation(t *testing.T) {
+	values := make([]ref.Val, 100)
+	for i := range values {
+		values[i] = types.Int(i)
+	}
+	it := &countingIterator{values: values}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	visited := 0
+	result := IterateRange(ctx, it, types.Int(0), func(acc, next ref.Val) (ref.Val, bool) {
+		visited++
+		if visited == 1 {
+			// Cancel after the first element so the loop must stop on its
+			// next boundary check rather than running to completion.
+			cancel()
+		}
+		return acc.(types.Int) + next.(types.Int), true
+	})
+
+	if visited != 1 {
+		t.Errorf("visit called %d times, wanted exactly 1 before cancellation was observed", visited)
+	}
+	if it.visited != 1 {
+		t.Errorf("iterator advanced %d times, wanted exactly 1", it.visited)
+	}
+	if _, isErr := result.(*types.Err); !isErr {
+		t.Errorf("IterateRange() = %v, wanted a canceled-evaluation error", result)
+	}
+}
+
+func TestIterateRangeCompletesWithoutCancellation(t *testing.T) {
+	values := []ref.Val{types.Int(1), types.Int(2), types.Int(3)}
+	it := &countingIterator{values: values}
+
+	result := IterateRange(context.Background(), it, types.Int(0), func(acc, next ref.Val) (ref.Val, bool) {
+		return acc.(types.Int) + next.(types.Int), true
+	})
+
+	if result != types.Int(6) {
+		t.Errorf("IterateRange() = %v, wanted 6", result)
+	}
+	if it.visited != len(values) {
+		t.Errorf("iterator advanced %d times, wanted %d", it.visited, len(values))
+	}
+}
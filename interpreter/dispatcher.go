@@ -0,0 +1,206 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+
+	"github.com/google/cel-go/common/functions"
+)
+
+// Dispatcher resolves function and operator calls to their OverloadContext
+// and invokes it, honoring any interceptors installed with SetInterceptors.
+// Before invoking a handler, every Dispatch* method verifies the first
+// operand satisfies OverloadContext.OperandTrait, short-circuits on a
+// types.Err or types.Unknown argument unless OverloadContext.NonStrict is
+// set, and checks the incoming context via functions.CheckCanceled — so a
+// canceled or deadline-exceeded context stops evaluation at the next call
+// boundary.
+type Dispatcher interface {
+	// Add registers overloads, keyed by their Operator name. Add returns an
+	// error if an overload with the same Operator has already been added.
+	Add(overloads ...*functions.OverloadContext) error
+
+	// FindOverload returns the overload registered for the given operator,
+	// if any.
+	FindOverload(operator string) (*functions.OverloadContext, bool)
+
+	// SetInterceptors installs the interceptor chains that wrap every
+	// DispatchUnary, DispatchBinary, and DispatchFunction call. Passing a
+	// nil slice for a given kind clears that chain.
+	SetInterceptors(unary []functions.UnaryInterceptor, binary []functions.BinaryInterceptor, function []functions.FunctionInterceptor)
+
+	// DispatchUnary resolves and invokes the unary overload registered for
+	// operator, running it through the installed UnaryInterceptor chain.
+	DispatchUnary(ctx context.Context, operator string, value ref.Val) (ref.Val, error)
+
+	// DispatchBinary resolves and invokes the binary overload registered
+	// for operator, running it through the installed BinaryInterceptor
+	// chain.
+	DispatchBinary(ctx context.Context, operator string, lhs, rhs ref.Val) (ref.Val, error)
+
+	// DispatchFunction resolves and invokes the function overload
+	// registered for operator, running it through the installed
+	// FunctionInterceptor chain.
+	DispatchFunction(ctx context.Context, operator string, values ...ref.Val) (ref.Val, error)
+
+	// DispatchStream resolves and invokes the stream overload registered
+	// for operator, materializing its result channel into a
+	// *types.StreamValue that comprehensions can iterate lazily. Stream
+	// overloads do not go through Unary/Binary/FunctionInterceptor chains,
+	// since there is no single result value to intercept.
+	DispatchStream(ctx context.Context, operator string, values ...ref.Val) (ref.Val, error)
+}
+
+// NewDispatcher creates a new Dispatcher with no overloads or interceptors
+// registered.
+func NewDispatcher() Dispatcher {
+	d := &defaultDispatcher{
+		overloads: make(map[string]*functions.OverloadContext),
+	}
+	// Install no-op chains so Dispatch* can call them unconditionally without
+	// requiring callers to invoke SetInterceptors first.
+	d.SetInterceptors(nil, nil, nil)
+	return d
+}
+
+type defaultDispatcher struct {
+	overloads     map[string]*functions.OverloadContext
+	unaryChain    functions.UnaryInterceptor
+	binaryChain   functions.BinaryInterceptor
+	functionChain functions.FunctionInterceptor
+}
+
+func (d *defaultDispatcher) Add(overloads ...*functions.OverloadContext) error {
+	for _, o := range overloads {
+		if _, found := d.overloads[o.Operator]; found {
+			return fmt.Errorf("overload already exists '%s'", o.Operator)
+		}
+		d.overloads[o.Operator] = o
+	}
+	return nil
+}
+
+func (d *defaultDispatcher) FindOverload(operator string) (*functions.OverloadContext, bool) {
+	o, found := d.overloads[operator]
+	return o, found
+}
+
+func (d *defaultDispatcher) SetInterceptors(unary []functions.UnaryInterceptor, binary []functions.BinaryInterceptor, function []functions.FunctionInterceptor) {
+	d.unaryChain = functions.ChainUnaryInterceptors(unary...)
+	d.binaryChain = functions.ChainBinaryInterceptors(binary...)
+	d.functionChain = functions.ChainFunctionInterceptors(function...)
+}
+
+func (d *defaultDispatcher) DispatchUnary(ctx context.Context, operator string, value ref.Val) (ref.Val, error) {
+	o, found := d.FindOverload(operator)
+	if !found || o.Unary == nil || !hasOperandTrait(o.OperandTrait, value) {
+		return nil, fmt.Errorf("no such unary overload: %s", operator)
+	}
+	if !o.NonStrict {
+		if errVal, isErr := strictArgError(value); isErr {
+			return errVal, nil
+		}
+	}
+	if errVal, canceled := functions.CheckCanceled(ctx); canceled {
+		return errVal, nil
+	}
+	return d.unaryChain(ctx, operator, value, o.Unary), nil
+}
+
+func (d *defaultDispatcher) DispatchBinary(ctx context.Context, operator string, lhs, rhs ref.Val) (ref.Val, error) {
+	o, found := d.FindOverload(operator)
+	if !found || o.Binary == nil || !hasOperandTrait(o.OperandTrait, lhs) {
+		return nil, fmt.Errorf("no such binary overload: %s", operator)
+	}
+	if !o.NonStrict {
+		if errVal, isErr := strictArgError(lhs, rhs); isErr {
+			return errVal, nil
+		}
+	}
+	if errVal, canceled := functions.CheckCanceled(ctx); canceled {
+		return errVal, nil
+	}
+	return d.binaryChain(ctx, operator, lhs, rhs, o.Binary), nil
+}
+
+func (d *defaultDispatcher) DispatchFunction(ctx context.Context, operator string, values ...ref.Val) (ref.Val, error) {
+	o, found := d.FindOverload(operator)
+	if !found || o.Function == nil {
+		return nil, fmt.Errorf("no such function overload: %s", operator)
+	}
+	if len(values) > 0 && !hasOperandTrait(o.OperandTrait, values[0]) {
+		return nil, fmt.Errorf("no such function overload: %s", operator)
+	}
+	if !o.NonStrict {
+		if errVal, isErr := strictArgError(values...); isErr {
+			return errVal, nil
+		}
+	}
+	if errVal, canceled := functions.CheckCanceled(ctx); canceled {
+		return errVal, nil
+	}
+	return d.functionChain(ctx, operator, values, o.Function), nil
+}
+
+// hasOperandTrait reports whether operand satisfies trait. A zero trait
+// means the overload is a global function, or otherwise imposes no operand
+// trait requirement, and always matches.
+func hasOperandTrait(trait int, operand ref.Val) bool {
+	if trait == 0 {
+		return true
+	}
+	return operand.Type().HasTrait(trait)
+}
+
+// strictArgError returns the first types.Err or types.Unknown value found
+// among values, so that strict (non-NonStrict) overloads short-circuit
+// instead of running on erroneous or unresolved input.
+func strictArgError(values ...ref.Val) (ref.Val, bool) {
+	for _, v := range values {
+		switch v.(type) {
+		case *types.Err, types.Unknown:
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (d *defaultDispatcher) DispatchStream(ctx context.Context, operator string, values ...ref.Val) (ref.Val, error) {
+	o, found := d.FindOverload(operator)
+	if !found || o.Stream == nil {
+		return nil, fmt.Errorf("no such stream overload: %s", operator)
+	}
+	if len(values) > 0 && !hasOperandTrait(o.OperandTrait, values[0]) {
+		return nil, fmt.Errorf("no such stream overload: %s", operator)
+	}
+	if !o.NonStrict {
+		if errVal, isErr := strictArgError(values...); isErr {
+			return errVal, nil
+		}
+	}
+	if errVal, canceled := functions.CheckCanceled(ctx); canceled {
+		return errVal, nil
+	}
+	ch, err := o.Stream(ctx, values...)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewStreamValue(ctx, ch), nil
+}
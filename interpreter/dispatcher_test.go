@@ -0,0 +1,261 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interpreter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/cel-go/common/functions"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+func TestDispatcherUnaryInterceptorChain(t *testing.T) {
+	d := NewDispatcher()
+	err := d.Add(&functions.OverloadContext{
+		Operator: "double",
+		Unary: func(_ context.Context, value ref.Val) ref.Val {
+			return types.Int(value.(types.Int) * 2)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	var order []string
+	trace := func(name string) functions.UnaryInterceptor {
+		return func(ctx context.Context, operator string, value ref.Val, handler functions.UnaryOpContext) ref.Val {
+			order = append(order, name+":before")
+			out := handler(ctx, value)
+			order = append(order, name+":after")
+			return out
+		}
+	}
+	d.SetInterceptors(
+		[]functions.UnaryInterceptor{trace("outer"), trace("inner")},
+		nil,
+		nil,
+	)
+
+	out, err := d.DispatchUnary(context.Background(), "double", types.Int(21))
+	if err != nil {
+		t.Fatalf("DispatchUnary() failed: %v", err)
+	}
+	if out != types.Int(42) {
+		t.Errorf("DispatchUnary() = %v, wanted 42", out)
+	}
+	want := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(order) != len(want) {
+		t.Fatalf("interceptor call order = %v, wanted %v", order, want)
+	}
+	for i, name := range want {
+		if order[i] != name {
+			t.Errorf("interceptor call order[%d] = %s, wanted %s", i, order[i], name)
+		}
+	}
+}
+
+func TestDispatcherNoSuchOverload(t *testing.T) {
+	d := NewDispatcher()
+	if _, err := d.DispatchUnary(context.Background(), "missing", types.Int(1)); err == nil {
+		t.Error("DispatchUnary() succeeded for unregistered operator, wanted error")
+	}
+}
+
+func TestDispatcherWorksWithoutSetInterceptors(t *testing.T) {
+	d := NewDispatcher()
+	err := d.Add(&functions.OverloadContext{
+		Operator: "double",
+		Unary: func(_ context.Context, value ref.Val) ref.Val {
+			return types.Int(value.(types.Int) * 2)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	out, err := d.DispatchUnary(context.Background(), "double", types.Int(21))
+	if err != nil {
+		t.Fatalf("DispatchUnary() failed: %v", err)
+	}
+	if out != types.Int(42) {
+		t.Errorf("DispatchUnary() = %v, wanted 42", out)
+	}
+}
+
+func TestDispatcherOperandTraitMismatch(t *testing.T) {
+	d := NewDispatcher()
+	err := d.Add(&functions.OverloadContext{
+		Operator:     "size",
+		OperandTrait: traits.SizerType,
+		Unary: func(_ context.Context, value ref.Val) ref.Val {
+			return value.(traits.Sizer).Size()
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if _, err := d.DispatchUnary(context.Background(), "size", types.Int(1)); err == nil {
+		t.Error("DispatchUnary() succeeded for an operand missing the required trait, wanted error")
+	}
+}
+
+func TestDispatcherStrictShortCircuitsOnError(t *testing.T) {
+	d := NewDispatcher()
+	called := false
+	err := d.Add(&functions.OverloadContext{
+		Operator: "identity",
+		Unary: func(_ context.Context, value ref.Val) ref.Val {
+			called = true
+			return value
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	argErr := types.NewErr("boom")
+	out, err := d.DispatchUnary(context.Background(), "identity", argErr)
+	if err != nil {
+		t.Fatalf("DispatchUnary() failed: %v", err)
+	}
+	if out != ref.Val(argErr) {
+		t.Errorf("DispatchUnary() = %v, wanted the argument error to be returned unevaluated", out)
+	}
+	if called {
+		t.Error("Unary handler was invoked for a strict overload despite an erroneous argument")
+	}
+}
+
+func TestDispatcherNonStrictRunsOnError(t *testing.T) {
+	d := NewDispatcher()
+	called := false
+	err := d.Add(&functions.OverloadContext{
+		Operator:  "identity",
+		NonStrict: true,
+		Unary: func(_ context.Context, value ref.Val) ref.Val {
+			called = true
+			return value
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if _, err := d.DispatchUnary(context.Background(), "identity", types.NewErr("boom")); err != nil {
+		t.Fatalf("DispatchUnary() failed: %v", err)
+	}
+	if !called {
+		t.Error("Unary handler was not invoked for a NonStrict overload despite NonStrict being set")
+	}
+}
+
+func TestDispatcherStreamStrictShortCircuitsOnError(t *testing.T) {
+	d := NewDispatcher()
+	called := false
+	err := d.Add(&functions.OverloadContext{
+		Operator: "range",
+		Stream: func(_ context.Context, _ ...ref.Val) (<-chan ref.Val, error) {
+			called = true
+			ch := make(chan ref.Val)
+			close(ch)
+			return ch, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	argErr := types.NewErr("boom")
+	out, err := d.DispatchStream(context.Background(), "range", argErr)
+	if err != nil {
+		t.Fatalf("DispatchStream() failed: %v", err)
+	}
+	if out != ref.Val(argErr) {
+		t.Errorf("DispatchStream() = %v, wanted the argument error to be returned unevaluated", out)
+	}
+	if called {
+		t.Error("Stream handler was invoked for a strict overload despite an erroneous argument")
+	}
+}
+
+func TestDispatcherStream(t *testing.T) {
+	d := NewDispatcher()
+	err := d.Add(&functions.OverloadContext{
+		Operator: "range",
+		Stream: func(_ context.Context, _ ...ref.Val) (<-chan ref.Val, error) {
+			ch := make(chan ref.Val, 3)
+			ch <- types.Int(1)
+			ch <- types.Int(2)
+			ch <- types.Int(3)
+			close(ch)
+			return ch, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	out, err := d.DispatchStream(context.Background(), "range")
+	if err != nil {
+		t.Fatalf("DispatchStream() failed: %v", err)
+	}
+	stream, ok := out.(*types.StreamValue)
+	if !ok {
+		t.Fatalf("DispatchStream() = %T, wanted *types.StreamValue", out)
+	}
+	it := stream.Iterator()
+	var got []int64
+	for it.HasNext() == types.True {
+		got = append(got, int64(it.Next().(types.Int)))
+	}
+	want := []int64{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("iterated %v, wanted %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("iterated[%d] = %d, wanted %d", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDispatcherStreamCanceledBeforeInvocation(t *testing.T) {
+	d := NewDispatcher()
+	called := false
+	err := d.Add(&functions.OverloadContext{
+		Operator: "range",
+		Stream: func(_ context.Context, _ ...ref.Val) (<-chan ref.Val, error) {
+			called = true
+			ch := make(chan ref.Val)
+			close(ch)
+			return ch, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out, err := d.DispatchStream(ctx, "range")
+	if err != nil {
+		t.Fatalf("DispatchStream() failed: %v", err)
+	}
+	if called {
+		t.Error("Stream handler was invoked despite an already-canceled context")
+	}
+	if _, isErr := out.(*types.Err); !isErr {
+		t.Errorf("DispatchStream() = %v, wanted a canceled-evaluation error", out)
+	}
+}